@@ -66,6 +66,13 @@ const CONFIG_TEMPLATE: &str = r#"# ───────────────
 name = __PROJECT_NAME__                        # Project name (resource prefix)
 region = __PROJECT_REGION__                    # AWS region
 
+# Cost-allocation tags applied to the DSQL cluster and DynamoDB tables.
+# `dsqld infra apply` refuses to create resources until all three are set.
+[project.tags]
+team = ""                                      # Owning team (required)
+env = "dev"                                    # Environment (required)
+service = "temporal"                           # Service name (required)
+
 # ─── Aurora DSQL Persistence ─────────────────────────────────────────────────
 # DSQL has two cluster-wide constraints that drive this configuration:
 #   1. Connection rate limit: 100 new connections/sec (sustained), 1000 burst
@@ -93,6 +100,16 @@ max_idle_conns = 50                            # Must equal max_conns
 connection_timeout = "30s"                     # Per-connection creation timeout
 max_conn_lifetime = "55m"                      # Under DSQL's 60-minute hard limit
 
+# ─── TLS ─────────────────────────────────────────────────────────────────────
+# Connections verify the server certificate against the cluster endpoint
+# (verify-full), pinned to the Amazon Trust Services roots (AmazonRootCA1–4)
+# shipped in dev/certs/ — mounted read-only at /etc/temporal/certs. Point
+# ca_file elsewhere (e.g. /etc/ssl/certs/ca-certificates.crt) to override.
+
+[dsql.tls]
+ca_file = "/etc/temporal/certs/amazon-root-ca-bundle.pem" # CA bundle inside the containers
+enable_host_verification = true                # Check cert hostname against the endpoint
+
 # ─── Layer 1: Connection Reservoir ───────────────────────────────────────────
 # Pre-creates connections in a background goroutine so driver.Open() returns
 # instantly from a ready buffer.
@@ -155,6 +172,18 @@ image = "temporal-dsql-server:latest"          # Docker image for Temporal servi
 [dynamodb]
 rate_limiter_table = ""                        # Default: {project.name}-dsql-rate-limiter
 conn_lease_table = ""                          # Default: {project.name}-dsql-conn-lease
+
+# ─── Profiles ────────────────────────────────────────────────────────────────
+# Named overlays selected with `dsqld --env <name>` (or DSQLD_ENV). A profile
+# is a partial config: fields set here replace the values above, everything
+# else is inherited — except the provisioned cluster identifier and table
+# names, which each profile gets from its own `dsqld --env <name> infra apply`.
+#
+# [profiles.stage.project]
+# name = "temporal-stage"                      # Separate resource prefix per env
+#
+# [profiles.stage.dsql]
+# user = "temporal"
 "#;
 
 #[cfg(test)]
@@ -34,9 +34,9 @@ pub enum DevAction {
     },
 }
 
-pub fn dev(action: DevAction) -> Result<()> {
+pub fn dev(action: DevAction, env: Option<&str>) -> Result<()> {
     if action_requires_env(&action) {
-        prepare_env()?;
+        prepare_env(env)?;
     }
 
     match action {
@@ -72,8 +72,8 @@ fn up(detach: bool) -> Result<()> {
     compose(&args)
 }
 
-fn prepare_env() -> Result<()> {
-    let config = dsqld_config::load_config(&paths::config_file())?;
+fn prepare_env(env: Option<&str>) -> Result<()> {
+    let config = dsqld_config::load_config_for_env(&paths::config_file(), env)?;
     dsqld_config::validate::validate(&config)?;
 
     let env_content = dsqld_config::env::generate_env(&config)?;
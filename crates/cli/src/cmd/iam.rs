@@ -0,0 +1,301 @@
+use clap::Subcommand;
+use dsqld_config::ProjectConfig;
+use eyre::{Result, bail};
+use serde_json::{Value, json};
+
+use crate::cmd::infra::{conn_lease_table_name, rate_limiter_table_name, table_name_for_destroy};
+use crate::paths;
+
+#[derive(Debug, Subcommand)]
+pub enum IamAction {
+    /// Print a least-privilege IAM policy for the configured cluster and user
+    Policy {
+        /// AWS account ID that owns the cluster
+        #[arg(long, env = "AWS_ACCOUNT_ID")]
+        account_id: String,
+        /// Also grant the actions used by `dsqld infra apply/destroy/status`
+        #[arg(long)]
+        infra: bool,
+    },
+}
+
+pub fn iam(action: IamAction, env: Option<&str>) -> Result<()> {
+    match action {
+        IamAction::Policy { account_id, infra } => policy(&account_id, infra, env),
+    }
+}
+
+fn policy(account_id: &str, infra: bool, env: Option<&str>) -> Result<()> {
+    let config = dsqld_config::load_config_for_env(&paths::config_file(), env)?;
+
+    if account_id.len() != 12 || !account_id.chars().all(|c| c.is_ascii_digit()) {
+        bail!("--account-id must be a 12-digit AWS account ID, got '{account_id}'");
+    }
+    if config.dsql.identifier.is_empty() {
+        if !infra {
+            bail!(
+                "dsql.identifier is empty — run 'dsqld infra apply' first or pass --infra \
+                 to generate a bootstrap policy"
+            );
+        }
+        eprintln!("  note: dsql.identifier is empty — cluster statements are scoped to cluster/*");
+    }
+
+    let policy = build_policy(&config, account_id, infra);
+    println!("{}", serde_json::to_string_pretty(&policy)?);
+    Ok(())
+}
+
+/// Build the policy document. The connect action follows the DSQL rule that
+/// only the `admin` role needs `DbConnectAdmin`; every other role uses
+/// `DbConnect`. DynamoDB item access is only granted for enabled layers, on
+/// the table names the services are configured with. CloudWatch reads back
+/// the Grafana DSQL panels, which share the same `~/.aws` credentials.
+fn build_policy(config: &ProjectConfig, account_id: &str, infra: bool) -> Value {
+    let region = &config.project.region;
+    let project = &config.project.name;
+
+    let any_cluster = format!("arn:aws:dsql:{region}:{account_id}:cluster/*");
+    let cluster = if config.dsql.identifier.is_empty() {
+        any_cluster.clone()
+    } else {
+        format!(
+            "arn:aws:dsql:{region}:{account_id}:cluster/{}",
+            config.dsql.identifier
+        )
+    };
+
+    let table_arn = |name: &str| format!("arn:aws:dynamodb:{region}:{account_id}:table/{name}");
+    let derived_rate = rate_limiter_table_name(project);
+    let derived_lease = conn_lease_table_name(project);
+    let rate_table = table_arn(&configured_or_derived(
+        &config.dsql.rate_coordination.table_name,
+        &derived_rate,
+    ));
+    let lease_table = table_arn(&configured_or_derived(
+        &config.dsql.conn_lease.table_name,
+        &derived_lease,
+    ));
+
+    let connect_action = if config.dsql.user == "admin" {
+        "dsql:DbConnectAdmin"
+    } else {
+        "dsql:DbConnect"
+    };
+
+    let mut statements = vec![json!({
+        "Sid": "DsqlConnect",
+        "Effect": "Allow",
+        "Action": [connect_action],
+        "Resource": cluster,
+    })];
+
+    let mut coordination_tables = Vec::new();
+    if config.dsql.rate_coordination.enabled {
+        coordination_tables.push(rate_table.clone());
+    }
+    if config.dsql.conn_lease.enabled {
+        coordination_tables.push(lease_table.clone());
+    }
+    if !coordination_tables.is_empty() {
+        statements.push(json!({
+            "Sid": "ConnectionCoordination",
+            "Effect": "Allow",
+            "Action": [
+                "dynamodb:GetItem",
+                "dynamodb:PutItem",
+                "dynamodb:UpdateItem",
+                "dynamodb:DeleteItem",
+            ],
+            "Resource": coordination_tables,
+        }));
+    }
+
+    statements.push(json!({
+        "Sid": "GrafanaCloudWatchRead",
+        "Effect": "Allow",
+        "Action": [
+            "cloudwatch:GetMetricData",
+            "cloudwatch:GetMetricStatistics",
+            "cloudwatch:ListMetrics",
+            "tag:GetResources",
+        ],
+        "Resource": "*",
+    }));
+
+    if infra {
+        // `infra apply` always creates the derived table names; `infra destroy`
+        // may target the configured ones instead.
+        let mut lifecycle_tables = vec![
+            table_arn(&derived_rate),
+            table_arn(&derived_lease),
+            table_arn(&table_name_for_destroy(
+                &config.dsql.rate_coordination.table_name,
+                &config.dynamodb.rate_limiter_table,
+                &derived_rate,
+            )),
+            table_arn(&table_name_for_destroy(
+                &config.dsql.conn_lease.table_name,
+                &config.dynamodb.conn_lease_table,
+                &derived_lease,
+            )),
+        ];
+        lifecycle_tables.sort();
+        lifecycle_tables.dedup();
+
+        // CreateCluster cannot be scoped to an ID that does not exist yet.
+        statements.push(json!({
+            "Sid": "DsqlClusterCreate",
+            "Effect": "Allow",
+            "Action": ["dsql:CreateCluster", "dsql:TagResource"],
+            "Resource": any_cluster,
+        }));
+        statements.push(json!({
+            "Sid": "DsqlClusterManage",
+            "Effect": "Allow",
+            "Action": ["dsql:GetCluster", "dsql:UpdateCluster", "dsql:DeleteCluster"],
+            "Resource": cluster,
+        }));
+        statements.push(json!({
+            "Sid": "DsqlClusterDiscovery",
+            "Effect": "Allow",
+            "Action": ["dsql:ListClusters"],
+            "Resource": "*",
+        }));
+        statements.push(json!({
+            "Sid": "DynamoDbTableLifecycle",
+            "Effect": "Allow",
+            "Action": [
+                "dynamodb:CreateTable",
+                "dynamodb:DescribeTable",
+                "dynamodb:DeleteTable",
+                "dynamodb:DescribeTimeToLive",
+                "dynamodb:ListTagsOfResource",
+                "dynamodb:UpdateTimeToLive",
+                "dynamodb:TagResource",
+            ],
+            "Resource": lifecycle_tables,
+        }));
+    }
+
+    json!({
+        "Version": "2012-10-17",
+        "Statement": statements,
+    })
+}
+
+fn configured_or_derived(configured: &str, derived: &str) -> String {
+    if configured.is_empty() {
+        derived.to_string()
+    } else {
+        configured.to_string()
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn config(user: &str) -> ProjectConfig {
+        let mut config = ProjectConfig::default();
+        config.dsql.identifier = "abc123".into();
+        config.dsql.user = user.into();
+        config
+    }
+
+    fn sids(policy: &Value) -> Vec<&str> {
+        policy["Statement"]
+            .as_array()
+            .unwrap()
+            .iter()
+            .map(|s| s["Sid"].as_str().unwrap())
+            .collect()
+    }
+
+    #[test]
+    fn admin_user_gets_db_connect_admin_on_the_cluster() {
+        let policy = build_policy(&config("admin"), "123456789012", false);
+        let connect = &policy["Statement"][0];
+        assert_eq!(connect["Action"], json!(["dsql:DbConnectAdmin"]));
+        assert_eq!(
+            connect["Resource"],
+            "arn:aws:dsql:eu-west-1:123456789012:cluster/abc123"
+        );
+    }
+
+    #[test]
+    fn non_admin_user_gets_db_connect_only() {
+        let policy = build_policy(&config("temporal"), "123456789012", false);
+        assert_eq!(policy["Statement"][0]["Action"], json!(["dsql:DbConnect"]));
+        assert_eq!(
+            sids(&policy),
+            vec![
+                "DsqlConnect",
+                "ConnectionCoordination",
+                "GrafanaCloudWatchRead"
+            ]
+        );
+    }
+
+    #[test]
+    fn coordination_tables_follow_enabled_layers() {
+        let mut cfg = config("admin");
+        cfg.dsql.rate_coordination.table_name = "my-rate".into();
+        cfg.dsql.conn_lease.enabled = false;
+        let policy = build_policy(&cfg, "123456789012", false);
+        assert_eq!(
+            policy["Statement"][1]["Resource"],
+            json!(["arn:aws:dynamodb:eu-west-1:123456789012:table/my-rate"])
+        );
+
+        cfg.dsql.rate_coordination.enabled = false;
+        let policy = build_policy(&cfg, "123456789012", false);
+        assert_eq!(sids(&policy), vec!["DsqlConnect", "GrafanaCloudWatchRead"]);
+    }
+
+    #[test]
+    fn infra_adds_lifecycle_statements() {
+        let policy = build_policy(&config("admin"), "123456789012", true);
+        assert_eq!(
+            sids(&policy),
+            vec![
+                "DsqlConnect",
+                "ConnectionCoordination",
+                "GrafanaCloudWatchRead",
+                "DsqlClusterCreate",
+                "DsqlClusterManage",
+                "DsqlClusterDiscovery",
+                "DynamoDbTableLifecycle",
+            ]
+        );
+        assert_eq!(
+            policy["Statement"][3]["Resource"],
+            "arn:aws:dsql:eu-west-1:123456789012:cluster/*"
+        );
+        assert_eq!(
+            policy["Statement"][6]["Resource"],
+            json!([
+                "arn:aws:dynamodb:eu-west-1:123456789012:table/temporal-dev-dsql-conn-lease",
+                "arn:aws:dynamodb:eu-west-1:123456789012:table/temporal-dev-dsql-rate-limiter",
+            ])
+        );
+    }
+
+    #[test]
+    fn infra_lifecycle_covers_derived_and_configured_tables() {
+        let mut cfg = config("admin");
+        cfg.dsql.rate_coordination.table_name = "custom-rate".into();
+        let policy = build_policy(&cfg, "123456789012", true);
+        let arn = |name: &str| format!("arn:aws:dynamodb:eu-west-1:123456789012:table/{name}");
+        assert_eq!(
+            policy["Statement"][6]["Resource"],
+            json!([
+                arn("custom-rate"),
+                arn("temporal-dev-dsql-conn-lease"),
+                arn("temporal-dev-dsql-rate-limiter"),
+            ])
+        );
+        // Item access follows the configured name the services use.
+        assert_eq!(policy["Statement"][1]["Resource"][0], arn("custom-rate"));
+    }
+}
@@ -1,4 +1,4 @@
-use std::collections::HashMap;
+use std::collections::{BTreeMap, HashMap};
 use std::io::{self, Write};
 use std::time::Duration;
 
@@ -19,13 +19,13 @@ pub enum InfraAction {
     Status,
 }
 
-pub fn infra(action: InfraAction) -> Result<()> {
+pub fn infra(action: InfraAction, env: Option<&str>) -> Result<()> {
     let rt = tokio::runtime::Runtime::new()?;
     rt.block_on(async {
         match action {
-            InfraAction::Apply => apply().await,
-            InfraAction::Destroy => destroy().await,
-            InfraAction::Status => status().await,
+            InfraAction::Apply => apply(env).await,
+            InfraAction::Destroy => destroy(env).await,
+            InfraAction::Status => status(env).await,
         }
     })
 }
@@ -47,22 +47,102 @@ pub fn conn_lease_table_name(project: &str) -> String {
     format!("{project}-dsql-conn-lease")
 }
 
-/// Build standard resource tags.
-fn resource_tags(project: &str) -> HashMap<String, String> {
-    HashMap::from([
+// ─── Tagging ────────────────────────────────────────────────────────────────
+
+/// Cost-allocation tags every provisioned resource must carry.
+const REQUIRED_TAGS: [&str; 3] = ["team", "env", "service"];
+
+/// Build resource tags: the configured `[project.tags]` plus the standard
+/// tags. Standard tags win so discovery by `Name` can't be broken by config.
+fn resource_tags(project: &str, configured: &BTreeMap<String, String>) -> HashMap<String, String> {
+    let mut tags: HashMap<String, String> = configured.clone().into_iter().collect();
+    tags.extend([
         ("Name".into(), cluster_name(project)),
         ("Project".into(), project.into()),
         ("ManagedBy".into(), "dsqld-cli".into()),
-    ])
+    ]);
+    tags
+}
+
+/// Required tag keys that are absent or empty in `[project.tags]`.
+fn missing_required_tags(configured: &BTreeMap<String, String>) -> Vec<&'static str> {
+    REQUIRED_TAGS
+        .into_iter()
+        .filter(|key| configured.get(*key).is_none_or(|v| v.trim().is_empty()))
+        .collect()
+}
+
+/// Keys of the configured `[project.tags]` whose value on the resource
+/// differs, sorted for stable output. The standard `Name`/`Project`/`ManagedBy`
+/// tags are identity, not cost allocation, and are never reconciled.
+fn tag_drift(
+    expected: &BTreeMap<String, String>,
+    actual: Option<&HashMap<String, String>>,
+) -> Vec<String> {
+    let mut drift: Vec<String> = expected
+        .iter()
+        .filter(|(k, v)| actual.and_then(|a| a.get(*k)) != Some(*v))
+        .map(|(k, _)| k.clone())
+        .collect();
+    drift.sort();
+    drift
+}
+
+/// What `infra apply` does about the tags of a resource it adopted.
+#[derive(Debug, PartialEq, Eq)]
+enum Retag {
+    /// The configured cost-allocation tags already match.
+    InSync,
+    /// Owned by this project and drifted: apply these tags.
+    Apply(HashMap<String, String>),
+    /// Drifted, but its `Name` tag isn't this project's: warn, don't touch.
+    Foreign(Vec<String>),
+}
+
+/// Decide how to reconcile an adopted resource's tags. Ownership is judged
+/// by the `Name` tag `resource_tags` writes (`owner`); only the drifted
+/// `[project.tags]` keys are ever re-applied.
+fn retag_plan(
+    owner: &str,
+    configured: &BTreeMap<String, String>,
+    actual: Option<&HashMap<String, String>>,
+) -> Retag {
+    let drift = tag_drift(configured, actual);
+    if drift.is_empty() {
+        return Retag::InSync;
+    }
+    let owned = actual
+        .and_then(|t| t.get("Name"))
+        .is_some_and(|name| name == owner);
+    if !owned {
+        return Retag::Foreign(drift);
+    }
+    Retag::Apply(
+        configured
+            .iter()
+            .filter(|(k, _)| drift.contains(k))
+            .map(|(k, v)| (k.clone(), v.clone()))
+            .collect(),
+    )
 }
 
 // ─── Apply ──────────────────────────────────────────────────────────────────
 
-async fn apply() -> Result<()> {
-    let config = dsqld_config::load_config(&paths::config_file())?;
+async fn apply(env: Option<&str>) -> Result<()> {
+    let config = dsqld_config::load_config_for_env(&paths::config_file(), env)?;
     let project = &config.project.name;
     let region = &config.project.region;
 
+    // Refuse to create anything whose spend can't be attributed.
+    let missing = missing_required_tags(&config.project.tags);
+    if !missing.is_empty() {
+        bail!(
+            "missing required cost-allocation tags: {} — set them under [project.tags] in config.toml",
+            missing.join(", ")
+        );
+    }
+    let tags = resource_tags(project, &config.project.tags);
+
     let sdk_config = aws_config::defaults(aws_config::BehaviorVersion::latest())
         .region(aws_config::Region::new(region.clone()))
         .load()
@@ -97,7 +177,7 @@ async fn apply() -> Result<()> {
             }
             None => {
                 eprintln!("▸ creating DSQL cluster '{derived_name}'…");
-                create_dsql_cluster(&dsql_client, project).await?
+                create_dsql_cluster(&dsql_client, &tags).await?
             }
         }
     };
@@ -106,18 +186,40 @@ async fn apply() -> Result<()> {
     let endpoint = detail.endpoint().unwrap_or_default();
     eprintln!("  endpoint: {endpoint}");
 
+    // Bring adopted clusters in line with the configured cost-allocation
+    // tags — but only clusters this project owns.
+    match retag_plan(&derived_name, &config.project.tags, detail.tags()) {
+        Retag::InSync => {}
+        Retag::Foreign(drift) => eprintln!(
+            "  warning: not re-tagging cluster not named '{derived_name}' (drift on {})",
+            drift.join(", ")
+        ),
+        Retag::Apply(cost_tags) => {
+            let mut keys: Vec<&str> = cost_tags.keys().map(String::as_str).collect();
+            keys.sort();
+            eprintln!("  tagging cluster: {}", keys.join(", "));
+            dsql_client
+                .tag_resource()
+                .resource_arn(detail.arn())
+                .set_tags(Some(cost_tags))
+                .send()
+                .await
+                .map_err(|e| classify_aws_error("dsql:TagResource", e.into_service_error()))?;
+        }
+    }
+
     // 2. Create DynamoDB tables (idempotent — adopts existing)
     let rate_table = rate_limiter_table_name(project);
     let lease_table = conn_lease_table_name(project);
 
     eprintln!("▸ creating DynamoDB table '{rate_table}'…");
-    create_dynamodb_table(&ddb_client, &rate_table, project).await?;
+    create_dynamodb_table(&ddb_client, &rate_table, project, &config.project.tags).await?;
 
     eprintln!("▸ creating DynamoDB table '{lease_table}'…");
-    create_dynamodb_table(&ddb_client, &lease_table, project).await?;
+    create_dynamodb_table(&ddb_client, &lease_table, project, &config.project.tags).await?;
 
     // 3. Write provisioned identifiers back to config.toml
-    write_infra_to_config(&cluster_id, &rate_table, &lease_table)?;
+    write_infra_to_config(env, &cluster_id, &rate_table, &lease_table)?;
     eprintln!(
         "▸ wrote dsql.identifier + DynamoDB table names to {}",
         paths::config_file().display()
@@ -129,12 +231,13 @@ async fn apply() -> Result<()> {
 
 /// Create a DSQL cluster with deletion protection and tags, wait for ACTIVE
 /// using the SDK waiter, return the cluster identifier.
-async fn create_dsql_cluster(client: &aws_sdk_dsql::Client, project: &str) -> Result<String> {
-    let tags = resource_tags(project);
-
+async fn create_dsql_cluster(
+    client: &aws_sdk_dsql::Client,
+    tags: &HashMap<String, String>,
+) -> Result<String> {
     let create_output = client
         .create_cluster()
-        .set_tags(Some(tags))
+        .set_tags(Some(tags.clone()))
         .deletion_protection_enabled(true)
         .send()
         .await
@@ -163,7 +266,9 @@ async fn create_dynamodb_table(
     client: &aws_sdk_dynamodb::Client,
     table_name: &str,
     project: &str,
+    configured_tags: &BTreeMap<String, String>,
 ) -> Result<()> {
+    let mut adopted = false;
     let table_arn = match client
         .create_table()
         .table_name(table_name)
@@ -198,6 +303,7 @@ async fn create_dynamodb_table(
             let svc_err = e.into_service_error();
             if svc_err.is_resource_in_use_exception() {
                 eprintln!("  table already exists, adopting");
+                adopted = true;
                 let desc = client
                     .describe_table()
                     .table_name(table_name)
@@ -228,9 +334,27 @@ async fn create_dynamodb_table(
         eprintln!("  TTL already enabled");
     }
 
-    // Tag the table
-    let tags: Vec<aws_sdk_dynamodb::types::Tag> = resource_tags(project)
-        .into_iter()
+    // Tag the table. New tables get the full set; adopted ones follow the
+    // same ownership rule as adopted clusters.
+    let tags = if adopted {
+        let actual = dynamodb_table_tags(client, &table_arn).await?;
+        let owner = cluster_name(project);
+        match retag_plan(&owner, configured_tags, Some(&actual)) {
+            Retag::InSync => return Ok(()),
+            Retag::Foreign(drift) => {
+                eprintln!(
+                    "  warning: not re-tagging table not named '{owner}' (drift on {})",
+                    drift.join(", ")
+                );
+                return Ok(());
+            }
+            Retag::Apply(cost_tags) => cost_tags,
+        }
+    } else {
+        resource_tags(project, configured_tags)
+    };
+    let tags: Vec<aws_sdk_dynamodb::types::Tag> = tags
+        .iter()
         .map(|(k, v)| {
             aws_sdk_dynamodb::types::Tag::builder()
                 .key(k)
@@ -354,18 +478,27 @@ async fn enable_ttl(client: &aws_sdk_dynamodb::Client, table_name: &str) -> Resu
 /// - `dsql.rate_coordination.table_name` — rate limiter DynamoDB table
 /// - `dsql.conn_lease.table_name` — connection lease DynamoDB table
 /// - `dynamodb.rate_limiter_table` / `dynamodb.conn_lease_table` — mirrors
-fn write_infra_to_config(identifier: &str, rate_table: &str, lease_table: &str) -> Result<()> {
+///
+/// When a profile is selected the values are written under
+/// `[profiles.<env>]` so each environment keeps its own resources.
+fn write_infra_to_config(
+    env: Option<&str>,
+    identifier: &str,
+    rate_table: &str,
+    lease_table: &str,
+) -> Result<()> {
     let path = paths::config_file();
     let contents = std::fs::read_to_string(&path)
         .map_err(|_| eyre::eyre!("could not read {}", path.display()))?;
 
-    let updated = update_infra_config_toml(&contents, identifier, rate_table, lease_table)?;
+    let updated = update_infra_config_toml(&contents, env, identifier, rate_table, lease_table)?;
     std::fs::write(&path, updated)?;
     Ok(())
 }
 
 fn update_infra_config_toml(
     contents: &str,
+    env: Option<&str>,
     identifier: &str,
     rate_table: &str,
     lease_table: &str,
@@ -374,19 +507,24 @@ fn update_infra_config_toml(
         .parse::<toml_edit::DocumentMut>()
         .map_err(|e| eyre::eyre!("failed to parse config.toml as TOML document: {e}"))?;
 
-    doc["dsql"]["identifier"] = value(identifier);
-    doc["dsql"]["rate_coordination"]["table_name"] = value(rate_table);
-    doc["dsql"]["conn_lease"]["table_name"] = value(lease_table);
-    doc["dynamodb"]["rate_limiter_table"] = value(rate_table);
-    doc["dynamodb"]["conn_lease_table"] = value(lease_table);
+    let root = match env {
+        Some(name) => &mut doc["profiles"][name],
+        None => doc.as_item_mut(),
+    };
+
+    root["dsql"]["identifier"] = value(identifier);
+    root["dsql"]["rate_coordination"]["table_name"] = value(rate_table);
+    root["dsql"]["conn_lease"]["table_name"] = value(lease_table);
+    root["dynamodb"]["rate_limiter_table"] = value(rate_table);
+    root["dynamodb"]["conn_lease_table"] = value(lease_table);
 
     Ok(doc.to_string())
 }
 
 // ─── Destroy ────────────────────────────────────────────────────────────────
 
-async fn destroy() -> Result<()> {
-    let config = dsqld_config::load_config(&paths::config_file())?;
+async fn destroy(env: Option<&str>) -> Result<()> {
+    let config = dsqld_config::load_config_for_env(&paths::config_file(), env)?;
     let project = &config.project.name;
     let region = &config.project.region;
 
@@ -479,7 +617,13 @@ async fn destroy() -> Result<()> {
     Ok(())
 }
 
-fn table_name_for_destroy(dsql_table: &str, dynamodb_table: &str, derived_table: &str) -> String {
+/// Table `infra destroy` targets: the `dsql.*` name, then `dynamodb.*`, then
+/// the derived name.
+pub fn table_name_for_destroy(
+    dsql_table: &str,
+    dynamodb_table: &str,
+    derived_table: &str,
+) -> String {
     if !dsql_table.is_empty() {
         return dsql_table.to_string();
     }
@@ -562,8 +706,8 @@ async fn find_cluster_by_name(client: &aws_sdk_dsql::Client, name: &str) -> Resu
 
 // ─── Status ─────────────────────────────────────────────────────────────────
 
-async fn status() -> Result<()> {
-    let config = dsqld_config::load_config(&paths::config_file())?;
+async fn status(env: Option<&str>) -> Result<()> {
+    let config = dsqld_config::load_config_for_env(&paths::config_file(), env)?;
     let project = &config.project.name;
     let region = &config.project.region;
 
@@ -578,6 +722,15 @@ async fn status() -> Result<()> {
     eprintln!("project: {project}");
     eprintln!("region:  {region}\n");
 
+    let expected_tags = &config.project.tags;
+    let missing = missing_required_tags(&config.project.tags);
+    if !missing.is_empty() {
+        eprintln!(
+            "warning: [project.tags] is missing required keys: {}\n",
+            missing.join(", ")
+        );
+    }
+
     // DSQL cluster status
     if !config.dsql.identifier.is_empty() {
         let id = &config.dsql.identifier;
@@ -586,6 +739,7 @@ async fn status() -> Result<()> {
                 let endpoint = detail.endpoint().unwrap_or_default();
                 eprintln!("dsql cluster:  {id} ({})", detail.status().as_str());
                 eprintln!("dsql endpoint: {endpoint}");
+                print_tag_status("dsql tags:", expected_tags, detail.tags());
             }
             Err(e) => {
                 eprintln!("dsql cluster:  {id} (error: {e})");
@@ -598,6 +752,7 @@ async fn status() -> Result<()> {
                 let detail = client_get_cluster(&dsql_client, &id).await?;
                 let endpoint = detail.endpoint().unwrap_or_default();
                 eprintln!("dsql cluster:  {id} (ACTIVE, endpoint: {endpoint})");
+                print_tag_status("dsql tags:", expected_tags, detail.tags());
                 eprintln!(
                     "  hint: run `dsqld infra apply` to populate dsql.identifier in config.toml"
                 );
@@ -612,16 +767,68 @@ async fn status() -> Result<()> {
 
     // DynamoDB table status
     eprintln!();
-    describe_dynamodb_table(&ddb_client, &rate_limiter_table_name(project)).await?;
-    describe_dynamodb_table(&ddb_client, &conn_lease_table_name(project)).await?;
+    describe_dynamodb_table(
+        &ddb_client,
+        &rate_limiter_table_name(project),
+        expected_tags,
+    )
+    .await?;
+    describe_dynamodb_table(&ddb_client, &conn_lease_table_name(project), expected_tags).await?;
 
     Ok(())
 }
 
+/// Print whether a resource carries the configured cost-allocation tags.
+fn print_tag_status(
+    label: &str,
+    expected: &BTreeMap<String, String>,
+    actual: Option<&HashMap<String, String>>,
+) {
+    let drift = tag_drift(expected, actual);
+    if drift.is_empty() {
+        eprintln!("{label:<15}ok");
+    } else {
+        eprintln!(
+            "{label:<15}drift on {} (run `dsqld infra apply` to fix)",
+            drift.join(", ")
+        );
+    }
+}
+
+/// Read all tags on a DynamoDB table.
+async fn dynamodb_table_tags(
+    client: &aws_sdk_dynamodb::Client,
+    table_arn: &str,
+) -> Result<HashMap<String, String>> {
+    let mut tags = HashMap::new();
+    let mut next_token = None;
+    loop {
+        let resp = client
+            .list_tags_of_resource()
+            .resource_arn(table_arn)
+            .set_next_token(next_token)
+            .send()
+            .await
+            .map_err(|e| {
+                classify_aws_error("dynamodb:ListTagsOfResource", e.into_service_error())
+            })?;
+        tags.extend(
+            resp.tags()
+                .iter()
+                .map(|t| (t.key().to_string(), t.value().to_string())),
+        );
+        next_token = resp.next_token().map(str::to_string);
+        if next_token.is_none() {
+            return Ok(tags);
+        }
+    }
+}
+
 /// Print the status of a DynamoDB table.
 async fn describe_dynamodb_table(
     client: &aws_sdk_dynamodb::Client,
     table_name: &str,
+    expected_tags: &BTreeMap<String, String>,
 ) -> Result<()> {
     match client.describe_table().table_name(table_name).send().await {
         Ok(resp) => {
@@ -632,6 +839,10 @@ async fn describe_dynamodb_table(
                 .unwrap_or_else(|| "UNKNOWN".into());
             let item_count = resp.table().and_then(|t| t.item_count()).unwrap_or(0);
             eprintln!("dynamodb table: {table_name} ({status}, {item_count} items)");
+            if let Some(arn) = resp.table().and_then(|t| t.table_arn()) {
+                let actual = dynamodb_table_tags(client, arn).await?;
+                print_tag_status("  tags:", expected_tags, Some(&actual));
+            }
         }
         Err(e) => {
             let svc_err = e.into_service_error();
@@ -674,6 +885,77 @@ fn classify_aws_error<E: std::fmt::Display>(operation: &str, err: E) -> eyre::Re
 mod tests {
     use super::*;
 
+    fn tags(pairs: &[(&str, &str)]) -> BTreeMap<String, String> {
+        pairs
+            .iter()
+            .map(|(k, v)| (k.to_string(), v.to_string()))
+            .collect()
+    }
+
+    #[test]
+    fn required_tags_must_be_present_and_non_empty() {
+        assert_eq!(
+            missing_required_tags(&BTreeMap::new()),
+            vec!["team", "env", "service"]
+        );
+        let partial = tags(&[("team", "platform"), ("env", " "), ("service", "temporal")]);
+        assert_eq!(missing_required_tags(&partial), vec!["env"]);
+    }
+
+    #[test]
+    fn resource_tags_merge_config_without_overriding_standard_tags() {
+        let merged = resource_tags("dev", &tags(&[("team", "platform"), ("Name", "other")]));
+        assert_eq!(merged["team"], "platform");
+        assert_eq!(merged["Name"], "dev-dsql");
+        assert_eq!(merged["ManagedBy"], "dsqld-cli");
+    }
+
+    #[test]
+    fn tag_drift_reports_missing_and_changed_keys() {
+        let expected = tags(&[("team", "platform"), ("env", "dev")]);
+        let mut actual = resource_tags("dev", &expected);
+        assert!(tag_drift(&expected, Some(&actual)).is_empty());
+
+        actual.remove("team");
+        actual.insert("env".into(), "prod".into());
+        assert_eq!(tag_drift(&expected, Some(&actual)), vec!["env", "team"]);
+        assert_eq!(tag_drift(&expected, None), vec!["env", "team"]);
+    }
+
+    #[test]
+    fn retag_plan_only_applies_drifted_cost_tags_to_owned_resources() {
+        let configured = tags(&[("team", "platform"), ("env", "dev")]);
+        let mut actual = resource_tags("dev", &configured);
+        assert_eq!(
+            retag_plan("dev-dsql", &configured, Some(&actual)),
+            Retag::InSync
+        );
+
+        actual.insert("env".into(), "prod".into());
+        assert_eq!(
+            retag_plan("dev-dsql", &configured, Some(&actual)),
+            Retag::Apply(HashMap::from([("env".into(), "dev".into())]))
+        );
+
+        actual.insert("Name".into(), "someone-elses-dsql".into());
+        assert_eq!(
+            retag_plan("dev-dsql", &configured, Some(&actual)),
+            Retag::Foreign(vec!["env".into()])
+        );
+        assert_eq!(
+            retag_plan("dev-dsql", &configured, None),
+            Retag::Foreign(vec!["env".into(), "team".into()])
+        );
+    }
+
+    #[test]
+    fn tag_drift_ignores_standard_identity_tags() {
+        let expected = tags(&[("team", "platform")]);
+        let mut actual = resource_tags("other-project", &expected);
+        actual.insert("ManagedBy".into(), "terraform".into());
+        assert!(tag_drift(&expected, Some(&actual)).is_empty());
+    }
+
     #[test]
     fn destroy_prefers_dsql_table_name_from_config() {
         let selected = table_name_for_destroy("configured-dsql", "configured-ddb", "derived");
@@ -713,7 +995,7 @@ conn_lease_table = ""
 flag = true
 "#;
 
-        let updated = update_infra_config_toml(original, "cluster-1", "rate-1", "lease-1")
+        let updated = update_infra_config_toml(original, None, "cluster-1", "rate-1", "lease-1")
             .expect("update should succeed");
 
         assert!(updated.contains("# keep this comment"));
@@ -728,4 +1010,74 @@ flag = true
         assert_eq!(parsed.dynamodb.rate_limiter_table, "rate-1");
         assert_eq!(parsed.dynamodb.conn_lease_table, "lease-1");
     }
+
+    #[test]
+    fn profile_round_trip_stays_on_its_own_resources() {
+        let original = r#"[project]
+name = "temporal-dev"
+
+[dsql]
+identifier = "dev-cluster"
+
+[dsql.rate_coordination]
+table_name = "temporal-dev-dsql-rate-limiter"
+
+[dsql.conn_lease]
+table_name = "temporal-dev-dsql-conn-lease"
+
+[profiles.stage.project]
+name = "temporal-stage"
+"#;
+        let destroy_targets = |config: &dsqld_config::ProjectConfig| {
+            let project = &config.project.name;
+            (
+                table_name_for_destroy(
+                    &config.dsql.rate_coordination.table_name,
+                    &config.dynamodb.rate_limiter_table,
+                    &rate_limiter_table_name(project),
+                ),
+                table_name_for_destroy(
+                    &config.dsql.conn_lease.table_name,
+                    &config.dynamodb.conn_lease_table,
+                    &conn_lease_table_name(project),
+                ),
+            )
+        };
+
+        // Before apply: stage discovers by its own Name tag and derived tables.
+        let stage =
+            dsqld_config::parse_config(original, Some("stage")).expect("stage should parse");
+        assert_eq!(cluster_name(&stage.project.name), "temporal-stage-dsql");
+        let derived = (
+            "temporal-stage-dsql-rate-limiter".to_string(),
+            "temporal-stage-dsql-conn-lease".to_string(),
+        );
+        assert_eq!(destroy_targets(&stage), derived);
+
+        // Apply writes under the profile; stage reads it back, dev is untouched.
+        let updated = update_infra_config_toml(
+            original,
+            Some("stage"),
+            "stage-cluster",
+            &derived.0,
+            &derived.1,
+        )
+        .expect("update should succeed");
+
+        let stage =
+            dsqld_config::parse_config(&updated, Some("stage")).expect("stage should parse");
+        assert_eq!(stage.dsql.identifier, "stage-cluster");
+        assert_eq!(stage.dynamodb.conn_lease_table, derived.1);
+        assert_eq!(destroy_targets(&stage), derived);
+
+        let dev = dsqld_config::parse_config(&updated, None).expect("dev should parse");
+        assert_eq!(dev.dsql.identifier, "dev-cluster");
+        assert_eq!(
+            destroy_targets(&dev),
+            (
+                "temporal-dev-dsql-rate-limiter".to_string(),
+                "temporal-dev-dsql-conn-lease".to_string()
+            )
+        );
+    }
 }
@@ -1,5 +1,6 @@
 pub mod build;
 pub mod config;
 pub mod dev;
+pub mod iam;
 pub mod infra;
 pub mod schema;
@@ -1,7 +1,10 @@
+use std::path::{Path, PathBuf};
+
 use clap::Subcommand;
+use dsqld_config::model::DsqlSection;
 use eyre::{Result, bail};
 
-use crate::{exec, paths};
+use crate::{exec, lint, paths};
 
 const SCHEMA_NAME: &str = "dsql/temporal";
 const TOOL_IMAGE: &str = "temporal-dsql-tool:latest";
@@ -20,27 +23,47 @@ pub enum SchemaAction {
         #[arg(long, default_value = TOOL_IMAGE)]
         image: String,
     },
+    /// Check DDL/migration files for statements DSQL does not support
+    Lint {
+        /// SQL files or directories (searched recursively for *.sql)
+        #[arg(required = true)]
+        paths: Vec<PathBuf>,
+    },
+    /// List and lint the versioned migrations a schema upgrade would apply
+    DryRun {
+        /// Schema version currently applied to the cluster
+        #[arg(long)]
+        from: String,
+        /// Target schema version [default: newest version in --source, i.e.
+        /// what the checked-out Temporal release expects]
+        #[arg(long)]
+        to: Option<String>,
+        /// Path to temporal-dsql source repo
+        #[arg(long, env = "TEMPORAL_DSQL_PATH")]
+        source: Option<PathBuf>,
+    },
 }
 
-pub fn schema(action: SchemaAction) -> Result<()> {
+pub fn schema(action: SchemaAction, env: Option<&str>) -> Result<()> {
     match action {
         SchemaAction::Setup {
             version,
             overwrite,
             image,
-        } => setup(&version, overwrite, &image),
+        } => setup(&version, overwrite, &image, env),
+        SchemaAction::Lint { paths } => lint_files(&paths),
+        SchemaAction::DryRun { from, to, source } => dry_run(&from, to.as_deref(), source),
     }
 }
 
-fn setup(version: &str, overwrite: bool, image: &str) -> Result<()> {
-    let config = dsqld_config::load_config(&paths::config_file())?;
+fn setup(version: &str, overwrite: bool, image: &str, env: Option<&str>) -> Result<()> {
+    let config = dsqld_config::load_config_for_env(&paths::config_file(), env)?;
 
     if config.dsql.identifier.is_empty() {
         bail!("dsql.identifier is empty — run 'dsqld infra apply' first or set it in config.toml");
     }
 
     let dsql_endpoint = config.dsql.endpoint(&config.project.region);
-    let port = config.dsql.port.to_string();
     let region = &config.project.region;
 
     eprintln!("Schema setup:");
@@ -49,15 +72,18 @@ fn setup(version: &str, overwrite: bool, image: &str) -> Result<()> {
     eprintln!("  Database: {}", config.dsql.database);
     eprintln!("  Region:   {region}");
     eprintln!("  Version:  {version}");
+    eprintln!("  CA file:  {}", config.dsql.tls.ca_file);
     if overwrite {
         eprintln!("  Overwrite: yes (existing tables will be dropped)");
     }
     eprintln!();
 
     // temporal-dsql-tool lives in a Docker image built by `dsqld build temporal`.
-    // Run it via `docker run` with host AWS credentials and IMDS disabled.
+    // Run it via `docker run` with host AWS credentials and IMDS disabled, and
+    // the same CA bundles the server containers verify against.
     let home = std::env::var("HOME").map_err(|_| eyre::eyre!("HOME not set"))?;
     let aws_mount = format!("{home}/.aws:/home/temporal/.aws:ro");
+    let certs_mount = format!("{}:/etc/temporal/certs:ro", paths::certs_dir().display());
 
     let mut args = vec![
         "run",
@@ -66,6 +92,8 @@ fn setup(version: &str, overwrite: bool, image: &str) -> Result<()> {
         "host",
         "-v",
         &aws_mount,
+        "-v",
+        &certs_mount,
         "-e",
         "AWS_EC2_METADATA_DISABLED=true",
     ];
@@ -74,15 +102,7 @@ fn setup(version: &str, overwrite: bool, image: &str) -> Result<()> {
     args.push(image);
 
     // Tool arguments (after the image name)
-    let tool_args_owned = build_tool_args(
-        &dsql_endpoint,
-        &port,
-        &config.dsql.user,
-        &config.dsql.database,
-        region,
-        version,
-        overwrite,
-    );
+    let tool_args_owned = build_tool_args(&dsql_endpoint, region, &config.dsql, version, overwrite);
     let tool_args_refs: Vec<&str> = tool_args_owned.iter().map(|s| s.as_str()).collect();
     args.extend_from_slice(&tool_args_refs);
 
@@ -91,32 +111,393 @@ fn setup(version: &str, overwrite: bool, image: &str) -> Result<()> {
 
 fn build_tool_args(
     endpoint: &str,
-    port: &str,
-    user: &str,
-    database: &str,
     region: &str,
+    dsql: &DsqlSection,
     version: &str,
     overwrite: bool,
 ) -> Vec<String> {
+    let tls = &dsql.tls;
     let mut args = vec![
         "--endpoint".into(),
         endpoint.into(),
         "--port".into(),
-        port.into(),
+        dsql.port.to_string(),
         "--user".into(),
-        user.into(),
+        dsql.user.clone(),
         "--database".into(),
-        database.into(),
+        dsql.database.clone(),
         "--region".into(),
         region.into(),
+        // verify-full against the cluster endpoint, as the server does
+        "--tls".into(),
+        "--tls-ca-file".into(),
+        tls.ca_file.clone(),
+        "--tls-server-name".into(),
+        endpoint.into(),
+    ];
+    if !tls.enable_host_verification {
+        args.push("--tls-disable-host-verification".into());
+    }
+    args.extend([
         "setup-schema".into(),
         "--schema-name".into(),
         SCHEMA_NAME.into(),
         "--version".into(),
         version.into(),
-    ];
+    ]);
     if overwrite {
         args.push("--overwrite".into());
     }
     args
 }
+
+// ─── Lint ───────────────────────────────────────────────────────────────────
+
+/// Lint every SQL file under `paths`, printing `file:line: [rule] message`
+/// for each finding. Fails if any finding is reported.
+fn lint_files(paths: &[PathBuf]) -> Result<()> {
+    let mut files = Vec::new();
+    for path in paths {
+        collect_sql_files(path, &mut files)?;
+    }
+    if files.is_empty() {
+        bail!("no .sql files found");
+    }
+
+    let mut total = 0;
+    for file in &files {
+        total += lint_file(file)?;
+    }
+
+    if total > 0 {
+        bail!(
+            "{total} DSQL compatibility issue(s) in {} file(s)",
+            files.len()
+        );
+    }
+    eprintln!("✓ {} file(s) DSQL-compatible", files.len());
+    Ok(())
+}
+
+/// Lint a single file, printing each finding. Returns the finding count.
+fn lint_file(file: &Path) -> Result<usize> {
+    let sql = std::fs::read_to_string(file)
+        .map_err(|e| eyre::eyre!("could not read {}: {e}", file.display()))?;
+    let findings = lint::lint_sql(&sql);
+    for finding in &findings {
+        eprintln!(
+            "{}:{}: [{}] {}",
+            file.display(),
+            finding.line,
+            finding.rule,
+            finding.message
+        );
+    }
+    Ok(findings.len())
+}
+
+/// Collect `path` if it is a file, or all `*.sql` files beneath it (sorted)
+/// if it is a directory.
+fn collect_sql_files(path: &Path, files: &mut Vec<PathBuf>) -> Result<()> {
+    if !path.is_dir() {
+        if !path.exists() {
+            bail!("'{}' does not exist", path.display());
+        }
+        files.push(path.to_path_buf());
+        return Ok(());
+    }
+
+    let mut entries: Vec<PathBuf> = std::fs::read_dir(path)?
+        .map(|e| e.map(|e| e.path()))
+        .collect::<std::io::Result<_>>()?;
+    entries.sort();
+    for entry in entries {
+        if entry.is_dir() {
+            collect_sql_files(&entry, files)?;
+        } else if entry.extension().is_some_and(|ext| ext == "sql") {
+            files.push(entry);
+        }
+    }
+    Ok(())
+}
+
+// ─── Dry run ────────────────────────────────────────────────────────────────
+
+/// A `vMAJOR.MINOR` directory under the schema's `versioned/` tree.
+type SchemaVersion = (u32, u32);
+
+/// Resolve the upgrade path `from` → `to` (schema versions, not Temporal
+/// releases) against the versioned schema in the temporal-dsql repo, lint
+/// every file each manifest would apply and list the manual steps. Nothing is
+/// executed against the cluster.
+fn dry_run(from: &str, to: Option<&str>, source: Option<PathBuf>) -> Result<()> {
+    let from_v = parse_version(from).ok_or_else(|| eyre::eyre!("invalid --from '{from}'"))?;
+    let to_v = to
+        .map(|to| parse_version(to).ok_or_else(|| eyre::eyre!("invalid --to '{to}'")))
+        .transpose()?;
+
+    let source = source.unwrap_or_else(paths::temporal_dsql_dir);
+    let versioned = source.join("schema").join(SCHEMA_NAME).join("versioned");
+    if !versioned.is_dir() {
+        bail!(
+            "no versioned schema at '{}' — set --source or TEMPORAL_DSQL_PATH",
+            versioned.display()
+        );
+    }
+
+    let available = list_versions(&versioned)?;
+    let to_v =
+        match to_v {
+            Some(v) if !available.contains(&v) => bail!(
+                "target version {}.{} not found under '{}'",
+                v.0,
+                v.1,
+                versioned.display()
+            ),
+            Some(v) => v,
+            // The newest version is the one the checked-out release expects.
+            None => available.iter().copied().max().ok_or_else(|| {
+                eyre::eyre!("no vX.Y directories under '{}'", versioned.display())
+            })?,
+        };
+    let to = format!("{}.{}", to_v.0, to_v.1);
+    if to_v <= from_v {
+        bail!("target version {to} must be newer than --from ({from})");
+    }
+    let steps = upgrade_steps(&available, from_v, to_v);
+
+    eprintln!("Upgrade dry run: {from} → {to}");
+    eprintln!("  Schema: {}", versioned.display());
+    eprintln!("  Steps:  {}", steps.len());
+    eprintln!();
+
+    let mut reports = Vec::new();
+    for (major, minor) in &steps {
+        let dir = versioned.join(format!("v{major}.{minor}"));
+        let manifest = read_manifest(&dir)?;
+        eprintln!("▸ v{major}.{minor} ({} file(s))", manifest.files.len());
+        let mut failed = Vec::new();
+        for file in &manifest.files {
+            let count = lint_file(&dir.join(file))?;
+            let mark = if count == 0 { "✓" } else { "✗" };
+            eprintln!("  {mark} {file}");
+            if count > 0 {
+                failed.push((file.clone(), count));
+            }
+        }
+        reports.push(StepReport {
+            version: (*major, *minor),
+            description: manifest.description,
+            failed,
+        });
+    }
+
+    let notes = version_notes(&reports);
+    if !notes.is_empty() {
+        eprintln!("\nWhat changes:");
+        for note in &notes {
+            eprintln!("  {note}");
+        }
+    }
+
+    let manual = manual_steps(&reports);
+    if !manual.is_empty() {
+        eprintln!("\nRequired manual steps:");
+        for (i, step) in manual.iter().enumerate() {
+            eprintln!("  {}. {step}", i + 1);
+        }
+    }
+
+    eprintln!();
+    let issues: usize = reports
+        .iter()
+        .flat_map(|r| &r.failed)
+        .map(|(_, count)| count)
+        .sum();
+    if issues > 0 {
+        bail!(
+            "{issues} DSQL compatibility issue(s) — these statements need manual \
+             adaptation before upgrading"
+        );
+    }
+    eprintln!("✓ {} migration step(s) would apply cleanly", steps.len());
+    Ok(())
+}
+
+/// Parse `1.2` or `v1.2` into `(1, 2)`.
+fn parse_version(s: &str) -> Option<SchemaVersion> {
+    let (major, minor) = s.strip_prefix('v').unwrap_or(s).split_once('.')?;
+    Some((major.parse().ok()?, minor.parse().ok()?))
+}
+
+/// Versions strictly after `from` up to and including `to`, in order.
+fn upgrade_steps(
+    available: &[SchemaVersion],
+    from: SchemaVersion,
+    to: SchemaVersion,
+) -> Vec<SchemaVersion> {
+    let mut steps: Vec<SchemaVersion> = available
+        .iter()
+        .copied()
+        .filter(|v| *v > from && *v <= to)
+        .collect();
+    steps.sort();
+    steps
+}
+
+fn list_versions(versioned: &Path) -> Result<Vec<SchemaVersion>> {
+    let mut versions = Vec::new();
+    for entry in std::fs::read_dir(versioned)? {
+        let entry = entry?;
+        if entry.file_type()?.is_dir()
+            && let Some(v) = entry.file_name().to_str().and_then(parse_version)
+        {
+            versions.push(v);
+        }
+    }
+    Ok(versions)
+}
+
+/// Lint outcome for one version directory on the upgrade path.
+struct StepReport {
+    version: SchemaVersion,
+    description: String,
+    /// Files with DSQL findings, with their finding counts.
+    failed: Vec<(String, usize)>,
+}
+
+/// The fields of a version's manifest.json the dry run reports on.
+struct Manifest {
+    description: String,
+    files: Vec<String>,
+}
+
+/// Each version's manifest `Description` — changelog text, not work to do.
+fn version_notes(reports: &[StepReport]) -> Vec<String> {
+    reports
+        .iter()
+        .filter(|r| !r.description.is_empty())
+        .map(|r| format!("v{}.{}: {}", r.version.0, r.version.1, r.description))
+        .collect()
+}
+
+/// Work the upgrade cannot do by itself: every file that failed lint.
+fn manual_steps(reports: &[StepReport]) -> Vec<String> {
+    let mut steps = Vec::new();
+    for report in reports {
+        let (major, minor) = report.version;
+        for (file, count) in &report.failed {
+            steps.push(format!(
+                "v{major}.{minor}: adapt {file} for DSQL ({count} issue(s) above)"
+            ));
+        }
+    }
+    steps
+}
+
+/// Read `Description` and `SchemaUpdateCqlFiles` from a version's
+/// manifest.json (the field names are shared by Temporal's SQL and Cassandra
+/// tooling).
+fn read_manifest(dir: &Path) -> Result<Manifest> {
+    let path = dir.join("manifest.json");
+    let contents = std::fs::read_to_string(&path)
+        .map_err(|e| eyre::eyre!("could not read {}: {e}", path.display()))?;
+    let manifest: serde_json::Value = serde_json::from_str(&contents)
+        .map_err(|e| eyre::eyre!("invalid manifest {}: {e}", path.display()))?;
+    let files = manifest["SchemaUpdateCqlFiles"]
+        .as_array()
+        .ok_or_else(|| eyre::eyre!("{} has no SchemaUpdateCqlFiles", path.display()))?;
+    let files = files
+        .iter()
+        .map(|f| {
+            f.as_str()
+                .map(str::to_string)
+                .ok_or_else(|| eyre::eyre!("non-string entry in {}", path.display()))
+        })
+        .collect::<Result<_>>()?;
+    let description = manifest["Description"]
+        .as_str()
+        .unwrap_or_default()
+        .to_string();
+    Ok(Manifest { description, files })
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn tool_args_verify_tls_against_the_endpoint() {
+        let endpoint = "abc.dsql.eu-west-1.on.aws";
+        let mut dsql = DsqlSection::default();
+        let args = build_tool_args(endpoint, "eu-west-1", &dsql, "1.1", false);
+        let joined = args.join(" ");
+        assert!(joined.contains(
+            "--tls --tls-ca-file /etc/temporal/certs/amazon-root-ca-bundle.pem \
+             --tls-server-name abc.dsql.eu-west-1.on.aws setup-schema"
+        ));
+        assert!(!joined.contains("--tls-disable-host-verification"));
+
+        dsql.tls.enable_host_verification = false;
+        let args = build_tool_args(endpoint, "eu-west-1", &dsql, "1.1", true);
+        assert!(args.contains(&"--tls-disable-host-verification".to_string()));
+        assert_eq!(args.last().map(String::as_str), Some("--overwrite"));
+    }
+
+    #[test]
+    fn parses_versions_with_and_without_prefix() {
+        assert_eq!(parse_version("1.2"), Some((1, 2)));
+        assert_eq!(parse_version("v1.12"), Some((1, 12)));
+        assert_eq!(parse_version("v1"), None);
+        assert_eq!(parse_version("latest"), None);
+    }
+
+    #[test]
+    fn upgrade_steps_are_exclusive_of_from_and_ordered() {
+        let available = [(1, 10), (1, 1), (1, 3), (1, 2), (2, 0)];
+        assert_eq!(
+            upgrade_steps(&available, (1, 1), (1, 10)),
+            vec![(1, 2), (1, 3), (1, 10)]
+        );
+        assert!(upgrade_steps(&available, (1, 3), (1, 3)).is_empty());
+    }
+
+    #[test]
+    fn read_manifest_reads_description_and_update_list() {
+        let dir = std::env::temp_dir().join("dsqld-schema-test-manifest");
+        std::fs::create_dir_all(&dir).unwrap();
+        std::fs::write(
+            dir.join("manifest.json"),
+            r#"{"CurrVersion": "1.2", "Description": "add nexus tables", "SchemaUpdateCqlFiles": ["a.sql", "b.sql"]}"#,
+        )
+        .unwrap();
+
+        let manifest = read_manifest(&dir).unwrap();
+        assert_eq!(manifest.description, "add nexus tables");
+        assert_eq!(manifest.files, vec!["a.sql", "b.sql"]);
+
+        std::fs::remove_dir_all(&dir).ok();
+    }
+
+    #[test]
+    fn notes_and_manual_steps_are_reported_separately() {
+        let reports = [
+            StepReport {
+                version: (1, 2),
+                description: "add nexus tables".into(),
+                failed: vec![],
+            },
+            StepReport {
+                version: (1, 3),
+                description: String::new(),
+                failed: vec![("b.sql".into(), 2)],
+            },
+        ];
+        assert_eq!(version_notes(&reports), vec!["v1.2: add nexus tables"]);
+        assert_eq!(
+            manual_steps(&reports),
+            vec!["v1.3: adapt b.sql for DSQL (2 issue(s) above)"]
+        );
+        assert!(manual_steps(&reports[..1]).is_empty());
+    }
+}
@@ -0,0 +1,386 @@
+//! DSQL compatibility lint for DDL/migration files.
+//!
+//! A lightweight tokenizer (comments, string literals, quoted identifiers and
+//! dollar-quoted bodies are skipped) feeds per-statement rules for features
+//! Aurora DSQL rejects. Nothing here touches a cluster.
+
+/// A single DSQL incompatibility found in a SQL source.
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub struct Finding {
+    /// 1-based line of the offending token.
+    pub line: usize,
+    /// Short rule identifier, e.g. `serial`.
+    pub rule: &'static str,
+    pub message: &'static str,
+}
+
+#[derive(Debug, Clone, PartialEq, Eq)]
+struct Token {
+    /// Uppercased keyword/identifier, or a punctuation/placeholder token.
+    text: String,
+    line: usize,
+}
+
+/// Placeholder emitted for string literals, quoted identifiers and
+/// dollar-quoted bodies so they can never match a keyword.
+const OPAQUE: &str = "<opaque>";
+
+/// Lint a SQL source and return all findings in source order.
+pub fn lint_sql(sql: &str) -> Vec<Finding> {
+    let tokens = tokenize(sql);
+    let mut findings = Vec::new();
+    for stmt in tokens.split(|t| t.text == ";") {
+        if !stmt.is_empty() {
+            lint_statement(stmt, &mut findings);
+        }
+    }
+    findings.sort_by_key(|f| f.line);
+    findings
+}
+
+// ─── Rules ──────────────────────────────────────────────────────────────────
+
+fn lint_statement(stmt: &[Token], findings: &mut Vec<Finding>) {
+    let mut push = |tok: &Token, rule: &'static str, message: &'static str| {
+        findings.push(Finding {
+            line: tok.line,
+            rule,
+            message,
+        });
+    };
+
+    let head: Vec<&str> = stmt.iter().take(4).map(|t| t.text.as_str()).collect();
+    let is_create_index = matches!(head.as_slice(), ["CREATE", "INDEX", ..])
+        || matches!(head.as_slice(), ["CREATE", "UNIQUE", "INDEX", ..]);
+    let is_table_ddl =
+        matches!(head.as_slice(), ["CREATE" | "ALTER", ..]) && head.contains(&"TABLE");
+
+    match head.as_slice() {
+        ["TRUNCATE", ..] => push(
+            &stmt[0],
+            "truncate",
+            "TRUNCATE is not supported — use DELETE",
+        ),
+        ["CREATE", "EXTENSION", ..] => push(&stmt[1], "extension", "extensions are not supported"),
+        ["CREATE", "TRIGGER", ..]
+        | ["CREATE", "CONSTRAINT", "TRIGGER", ..]
+        | ["CREATE", "OR", "REPLACE", "TRIGGER"] => {
+            push(&stmt[0], "trigger", "triggers are not supported")
+        }
+        ["CREATE", "TEMP" | "TEMPORARY" | "UNLOGGED", "TABLE", ..]
+        | ["CREATE", "GLOBAL" | "LOCAL", "TEMP" | "TEMPORARY", "TABLE"] => push(
+            &stmt[1],
+            "temp-table",
+            "temporary and unlogged tables are not supported",
+        ),
+        _ => {}
+    }
+
+    // Parenthesis depth: table-level clauses such as PARTITION BY sit at 0,
+    // window functions (`OVER (PARTITION BY …)`) never do.
+    let mut depth = 0usize;
+    for (i, tok) in stmt.iter().enumerate() {
+        let next = stmt.get(i + 1).map(|t| t.text.as_str());
+        match tok.text.as_str() {
+            "(" => depth += 1,
+            ")" => depth = depth.saturating_sub(1),
+            _ => {}
+        }
+        let column_type = is_table_ddl && is_column_type(stmt, i);
+
+        match tok.text.as_str() {
+            "SERIAL" | "SERIAL2" | "SERIAL4" | "SERIAL8" | "SMALLSERIAL" | "BIGSERIAL"
+                if column_type =>
+            {
+                push(
+                    tok,
+                    "serial",
+                    "SERIAL types are not supported — generate IDs in the application",
+                )
+            }
+            // Both inline and table-level foreign keys carry REFERENCES;
+            // elsewhere (e.g. GRANT REFERENCES) it is a privilege name.
+            "REFERENCES" if is_table_ddl => push(
+                tok,
+                "foreign-key",
+                "foreign keys are not supported — drop the constraint and enforce in the application",
+            ),
+            "JSON" | "JSONB" if column_type => push(
+                tok,
+                "json-column",
+                "JSON/JSONB are runtime-only types — store as TEXT and cast in queries",
+            ),
+            "PARTITION" if is_table_ddl && depth == 0 && next == Some("BY") => push(
+                tok,
+                "partition",
+                "declarative partitioning is not supported",
+            ),
+            "INHERITS" => push(tok, "inherits", "table inheritance is not supported"),
+            "TABLESPACE" => push(tok, "tablespace", "tablespaces are not supported"),
+            "LANGUAGE" if next == Some("PLPGSQL") => push(
+                tok,
+                "plpgsql",
+                "PL/pgSQL is not supported — only LANGUAGE sql functions",
+            ),
+            "CONCURRENTLY" if is_create_index => push(
+                tok,
+                "index-concurrently",
+                "CREATE INDEX CONCURRENTLY is not supported — use CREATE INDEX ASYNC",
+            ),
+            "USING" if is_create_index && next.is_some_and(|m| m != "BTREE" && m != "(") => {
+                push(tok, "index-method", "only btree indexes are supported")
+            }
+            _ => {}
+        }
+    }
+}
+
+/// Whether `stmt[i]` is in column-type position: right after the name that
+/// opens a column definition (`(name`, `, name`, `ADD [COLUMN] name`) or
+/// after `ALTER COLUMN … [SET DATA] TYPE`.
+fn is_column_type(stmt: &[Token], i: usize) -> bool {
+    let text = |j: usize| stmt[j].text.as_str();
+    if i >= 1 && text(i - 1) == "TYPE" {
+        return true;
+    }
+    if i < 2 {
+        return false;
+    }
+    let name = text(i - 1);
+    let is_name = name == OPAQUE || name.starts_with(|c: char| c.is_alphanumeric() || c == '_');
+    is_name && matches!(text(i - 2), "(" | "," | "ADD" | "COLUMN" | "EXISTS")
+}
+
+// ─── Tokenizer ──────────────────────────────────────────────────────────────
+
+fn tokenize(sql: &str) -> Vec<Token> {
+    let chars: Vec<char> = sql.chars().collect();
+    let mut tokens = Vec::new();
+    let mut line = 1;
+    let mut i = 0;
+
+    // Advance past chars[i..end], counting newlines.
+    let skip_to = |i: &mut usize, line: &mut usize, end: usize| {
+        let end = end.min(chars.len());
+        *line += chars[*i..end].iter().filter(|&&c| c == '\n').count();
+        *i = end;
+    };
+
+    while i < chars.len() {
+        let c = chars[i];
+        let next = chars.get(i + 1).copied();
+
+        if c == '\n' {
+            line += 1;
+            i += 1;
+        } else if c.is_whitespace() {
+            i += 1;
+        } else if c == '-' && next == Some('-') {
+            let end = find_from(&chars, i, |c| c == '\n').unwrap_or(chars.len());
+            i = end;
+        } else if c == '/' && next == Some('*') {
+            let end = find_seq(&chars, i + 2, &['*', '/']).map_or(chars.len(), |e| e + 2);
+            skip_to(&mut i, &mut line, end);
+        } else if c == '\'' || c == '"' {
+            tokens.push(Token {
+                text: OPAQUE.into(),
+                line,
+            });
+            let end = closing_quote(&chars, i + 1, c, false);
+            skip_to(&mut i, &mut line, end);
+        } else if matches!(c, 'E' | 'e') && next == Some('\'') {
+            // Escape string constant: backslash escapes apply inside.
+            tokens.push(Token {
+                text: OPAQUE.into(),
+                line,
+            });
+            let end = closing_quote(&chars, i + 2, '\'', true);
+            skip_to(&mut i, &mut line, end);
+        } else if c == '$'
+            && let Some(tag) = dollar_tag(&chars, i)
+        {
+            tokens.push(Token {
+                text: OPAQUE.into(),
+                line,
+            });
+            let body_start = i + tag.len();
+            let end = find_seq(&chars, body_start, &tag).map_or(chars.len(), |e| e + tag.len());
+            skip_to(&mut i, &mut line, end);
+        } else if c.is_alphanumeric() || c == '_' {
+            let end = find_from(&chars, i, |c| {
+                !(c.is_alphanumeric() || c == '_' || c == '$')
+            })
+            .unwrap_or(chars.len());
+            let word: String = chars[i..end].iter().collect();
+            tokens.push(Token {
+                text: word.to_uppercase(),
+                line,
+            });
+            i = end;
+        } else if c == ':' && next == Some(':') {
+            tokens.push(Token {
+                text: "::".into(),
+                line,
+            });
+            i += 2;
+        } else {
+            tokens.push(Token {
+                text: c.to_string(),
+                line,
+            });
+            i += 1;
+        }
+    }
+    tokens
+}
+
+fn find_from(chars: &[char], start: usize, pred: impl Fn(char) -> bool) -> Option<usize> {
+    chars[start..]
+        .iter()
+        .position(|&c| pred(c))
+        .map(|p| start + p)
+}
+
+fn find_seq(chars: &[char], start: usize, seq: &[char]) -> Option<usize> {
+    if start > chars.len() {
+        return None;
+    }
+    chars[start..]
+        .windows(seq.len())
+        .position(|w| w == seq)
+        .map(|p| start + p)
+}
+
+/// Index just past the closing quote, treating a doubled quote as an escape
+/// and, for `E'...'` strings, a backslash as escaping the next character.
+fn closing_quote(chars: &[char], mut i: usize, quote: char, backslash: bool) -> usize {
+    while i < chars.len() {
+        if backslash && chars[i] == '\\' {
+            i += 2;
+            continue;
+        }
+        if chars[i] == quote {
+            if chars.get(i + 1) == Some(&quote) {
+                i += 2;
+                continue;
+            }
+            return i + 1;
+        }
+        i += 1;
+    }
+    chars.len()
+}
+
+/// Parse a dollar-quote opening tag (`$$` or `$name$`) starting at `start`.
+fn dollar_tag(chars: &[char], start: usize) -> Option<Vec<char>> {
+    let close = find_from(chars, start + 1, |c| !(c.is_alphanumeric() || c == '_'))?;
+    if chars[close] != '$' || chars.get(start + 1).is_some_and(|c| c.is_ascii_digit()) {
+        return None;
+    }
+    Some(chars[start..=close].to_vec())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn rules(sql: &str) -> Vec<(&'static str, usize)> {
+        lint_sql(sql)
+            .into_iter()
+            .map(|f| (f.rule, f.line))
+            .collect()
+    }
+
+    #[test]
+    fn clean_dsql_schema_has_no_findings() {
+        let sql = r#"
+CREATE TABLE executions (
+  shard_id INTEGER NOT NULL,
+  namespace_id BYTEA NOT NULL,
+  data BYTEA NOT NULL,
+  PRIMARY KEY (shard_id, namespace_id)
+);
+CREATE INDEX ASYNC by_shard ON executions (shard_id);
+SELECT '{"a": 1}'::jsonb;
+"#;
+        assert!(lint_sql(sql).is_empty(), "{:?}", lint_sql(sql));
+    }
+
+    #[test]
+    fn flags_unsupported_ddl_with_line_numbers() {
+        let sql = "CREATE TABLE t (\n  id BIGSERIAL PRIMARY KEY,\n  owner_id INT REFERENCES owners(id),\n  attrs JSONB\n);\nCREATE INDEX CONCURRENTLY i ON t USING gin (attrs);\nTRUNCATE t;\n";
+        assert_eq!(
+            rules(sql),
+            vec![
+                ("serial", 2),
+                ("foreign-key", 3),
+                ("json-column", 4),
+                ("index-concurrently", 6),
+                ("index-method", 6),
+                ("truncate", 7),
+            ]
+        );
+    }
+
+    #[test]
+    fn flags_statement_level_features() {
+        let sql = "CREATE EXTENSION pgcrypto;\n\
+                   CREATE TEMP TABLE scratch (id INT);\n\
+                   CREATE TRIGGER trg BEFORE INSERT ON t FOR EACH ROW EXECUTE FUNCTION f();\n\
+                   CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN RETURN NEW; END $$ LANGUAGE plpgsql;\n\
+                   CREATE TABLE p (id INT, CONSTRAINT fk FOREIGN KEY (id) REFERENCES q(id)) PARTITION BY RANGE (id);\n";
+        let found: Vec<&str> = lint_sql(sql).into_iter().map(|f| f.rule).collect();
+        assert_eq!(
+            found,
+            vec![
+                "extension",
+                "temp-table",
+                "trigger",
+                "plpgsql",
+                "foreign-key",
+                "partition",
+            ]
+        );
+    }
+
+    #[test]
+    fn escape_strings_do_not_swallow_following_statements() {
+        let sql = "INSERT INTO t VALUES (E'it\\'s', e'a\\\\');\nCREATE TABLE x (id SERIAL);\n";
+        assert_eq!(rules(sql), vec![("serial", 2)]);
+    }
+
+    #[test]
+    fn references_is_only_a_foreign_key_in_table_ddl() {
+        let sql = "GRANT REFERENCES ON t TO app;\nALTER TABLE t ADD CONSTRAINT fk FOREIGN KEY (a) REFERENCES u(id);\n";
+        assert_eq!(rules(sql), vec![("foreign-key", 2)]);
+    }
+
+    #[test]
+    fn json_return_types_are_allowed() {
+        let sql = "CREATE FUNCTION f() RETURNS jsonb AS $$ SELECT '{}'::jsonb $$ LANGUAGE sql;";
+        assert!(lint_sql(sql).is_empty(), "{:?}", lint_sql(sql));
+    }
+
+    #[test]
+    fn type_rules_only_fire_in_column_type_position() {
+        let sql = "CREATE TABLE devices (serial TEXT, json TEXT);\n\
+                   CREATE FUNCTION f(p jsonb) RETURNS int AS $$ SELECT 1 $$ LANGUAGE sql;\n\
+                   ALTER TABLE t ADD COLUMN IF NOT EXISTS doc jsonb;\n\
+                   ALTER TABLE t ALTER COLUMN id SET DATA TYPE bigserial;\n";
+        assert_eq!(rules(sql), vec![("json-column", 3), ("serial", 4)]);
+    }
+
+    #[test]
+    fn window_partition_by_is_not_declarative_partitioning() {
+        let sql = "CREATE VIEW v AS SELECT row_number() OVER (PARTITION BY a) FROM t;\n\
+                   UPDATE t SET n = s.n FROM (SELECT id, rank() OVER (PARTITION BY ns) AS n FROM t) s WHERE t.id = s.id;\n\
+                   CREATE TABLE c AS SELECT sum(x) OVER (PARTITION BY y) FROM t;\n";
+        assert!(lint_sql(sql).is_empty(), "{:?}", lint_sql(sql));
+    }
+
+    #[test]
+    fn ignores_keywords_in_comments_strings_and_quoted_identifiers() {
+        let sql = "-- id SERIAL\n/* REFERENCES\n TRUNCATE */\nCREATE TABLE t (\"serial\" TEXT DEFAULT 'jsonb references');\nCOMMENT ON TABLE t IS $doc$ TRUNCATE; FOREIGN KEY $doc$;\n";
+        assert!(lint_sql(sql).is_empty(), "{:?}", lint_sql(sql));
+    }
+}
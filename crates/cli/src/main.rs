@@ -1,11 +1,13 @@
 mod cmd;
 mod exec;
+mod lint;
 mod paths;
 
 use clap::{Parser, Subcommand};
 use cmd::build::BuildAction;
 use cmd::config::ConfigAction;
 use cmd::dev::DevAction;
+use cmd::iam::IamAction;
 use cmd::infra::InfraAction;
 use cmd::schema::SchemaAction;
 use eyre::Result;
@@ -13,6 +15,11 @@ use eyre::Result;
 #[derive(Debug, Parser)]
 #[command(name = "dsqld", about = "Temporal DSQL local development CLI")]
 struct Cli {
+    /// Config profile to apply from [profiles.<name>] in config.toml
+    /// (used by infra, schema, dev and iam; `config` and `build` ignore it)
+    #[arg(long, global = true, env = "DSQLD_ENV")]
+    env: Option<String>,
+
     #[command(subcommand)]
     command: Command,
 }
@@ -44,17 +51,24 @@ enum Command {
         #[command(subcommand)]
         action: DevAction,
     },
+    /// IAM policy generation
+    Iam {
+        #[command(subcommand)]
+        action: IamAction,
+    },
 }
 
 fn main() -> Result<()> {
     color_eyre::install()?;
     let cli = Cli::parse();
+    let env = cli.env.as_deref();
 
     match cli.command {
         Command::Config { action } => cmd::config::config(action),
-        Command::Infra { action } => cmd::infra::infra(action),
+        Command::Infra { action } => cmd::infra::infra(action, env),
         Command::Build { action } => cmd::build::build(action),
-        Command::Schema { action } => cmd::schema::schema(action),
-        Command::Dev { action } => cmd::dev::dev(action),
+        Command::Schema { action } => cmd::schema::schema(action, env),
+        Command::Dev { action } => cmd::dev::dev(action, env),
+        Command::Iam { action } => cmd::iam::iam(action, env),
     }
 }
@@ -18,6 +18,16 @@ pub fn config_file() -> PathBuf {
     root().join("config.toml")
 }
 
+/// CA bundles mounted read-only at `/etc/temporal/certs` in the containers.
+pub fn certs_dir() -> PathBuf {
+    root().join("dev/certs")
+}
+
+/// Default location of the temporal-dsql repo (sibling of this workspace).
+pub fn temporal_dsql_dir() -> PathBuf {
+    root().join("../temporal-dsql")
+}
+
 #[allow(dead_code)] // Part of the paths API per design (Req 12.2), not yet called
 pub fn docker_dir() -> PathBuf {
     root().join("docker")
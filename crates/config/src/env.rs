@@ -43,6 +43,15 @@ pub fn generate_env(config: &ProjectConfig) -> Result<String, ConfigError> {
         config.dsql.max_conn_lifetime
     ));
 
+    // TLS — server name is always the derived endpoint so host verification
+    // checks the certificate against the cluster we meant to reach.
+    lines.push(format!("TEMPORAL_SQL_CA_FILE={}", config.dsql.tls.ca_file));
+    lines.push(format!(
+        "TEMPORAL_SQL_TLS_ENABLE_HOST_VERIFICATION={}",
+        config.dsql.tls.enable_host_verification
+    ));
+    lines.push(format!("TEMPORAL_SQL_SERVER_NAME={dsql_endpoint}"));
+
     // Elasticsearch
     lines.push(format!(
         "TEMPORAL_ELASTICSEARCH_HOST={}",
@@ -186,6 +195,11 @@ mod tests {
         assert!(env.contains("TEMPORAL_SQL_MAX_CONNS=50"));
         assert!(env.contains("TEMPORAL_SQL_MAX_IDLE_CONNS=50"));
 
+        // TLS — verify-full against the derived endpoint, pinned to the Amazon roots
+        assert!(env.contains("TEMPORAL_SQL_CA_FILE=/etc/temporal/certs/amazon-root-ca-bundle.pem"));
+        assert!(env.contains("TEMPORAL_SQL_TLS_ENABLE_HOST_VERIFICATION=true"));
+        assert!(env.contains("TEMPORAL_SQL_SERVER_NAME=my-cluster-id.dsql.eu-west-1.on.aws"));
+
         // Region (both vars)
         assert!(env.contains("AWS_REGION=eu-west-1"));
         assert!(env.contains("TEMPORAL_SQL_AWS_REGION=eu-west-1"));
@@ -229,6 +243,7 @@ mod tests {
         config.dsql.reservoir.enabled = false;
         config.dsql.rate_coordination.table_name = "my-rate-table".to_string();
         config.dsql.conn_lease.table_name = "my-lease-table".to_string();
+        config.dsql.tls.ca_file = "/etc/ssl/certs/ca-certificates.crt".to_string();
 
         let env = generate_env(&config).unwrap();
 
@@ -241,6 +256,7 @@ mod tests {
         assert!(env.contains("DSQL_RESERVOIR_ENABLED=false"));
         assert!(env.contains("DSQL_DISTRIBUTED_RATE_LIMITER_TABLE=my-rate-table"));
         assert!(env.contains("DSQL_DISTRIBUTED_CONN_LEASE_TABLE=my-lease-table"));
+        assert!(env.contains("TEMPORAL_SQL_CA_FILE=/etc/ssl/certs/ca-certificates.crt"));
     }
 
     #[test]
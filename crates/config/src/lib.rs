@@ -9,6 +9,18 @@ use std::path::Path;
 
 /// Load and deserialize config.toml from the given path.
 pub fn load_config(path: &Path) -> Result<ProjectConfig, ConfigError> {
+    load_config_for_env(path, None)
+}
+
+/// Load config.toml and, if `env` is set, overlay the `[profiles.<env>]`
+/// table on top of the base config before deserializing.
+///
+/// Profiles are partial configs: any section or field present in the profile
+/// replaces the base value, everything else is inherited — except the
+/// resource identifiers `dsqld infra apply` writes back (see
+/// [`PROVISIONED_FIELDS`]), which a profile never inherits from the base, and
+/// the `env` cost-allocation tag, which defaults to the profile name.
+pub fn load_config_for_env(path: &Path, env: Option<&str>) -> Result<ProjectConfig, ConfigError> {
     let contents = std::fs::read_to_string(path).map_err(|source| {
         if source.kind() == std::io::ErrorKind::NotFound {
             ConfigError::NotFound(path.to_path_buf())
@@ -19,10 +31,86 @@ pub fn load_config(path: &Path) -> Result<ProjectConfig, ConfigError> {
             }
         }
     })?;
-    let config: ProjectConfig = toml::from_str(&contents)?;
+    parse_config(&contents, env)
+}
+
+/// Parse config.toml contents, applying the named profile if given.
+pub fn parse_config(contents: &str, env: Option<&str>) -> Result<ProjectConfig, ConfigError> {
+    let mut doc: toml::Table = toml::from_str(contents)?;
+    let profiles = doc.remove("profiles");
+
+    if let Some(name) = env {
+        let profile = profiles
+            .as_ref()
+            .and_then(|p| p.get(name))
+            .and_then(|p| p.as_table())
+            .ok_or_else(|| ConfigError::UnknownProfile(name.to_string()))?;
+        for path in PROVISIONED_FIELDS {
+            remove_path(&mut doc, path);
+        }
+        remove_path(&mut doc, &["project", "tags", "env"]);
+        merge_tables(&mut doc, profile.clone());
+
+        // Spend under a profile is attributed to it unless it says otherwise.
+        let project = doc
+            .entry("project")
+            .or_insert_with(|| toml::Value::Table(toml::Table::new()));
+        if let toml::Value::Table(project) = project {
+            let tags = project
+                .entry("tags")
+                .or_insert_with(|| toml::Value::Table(toml::Table::new()));
+            if let toml::Value::Table(tags) = tags {
+                tags.entry("env")
+                    .or_insert_with(|| toml::Value::String(name.to_string()));
+            }
+        }
+    }
+
+    let config: ProjectConfig = doc.try_into()?;
     Ok(config)
 }
 
+/// Fields written by `dsqld infra apply` for the resources of one project.
+/// Inheriting them would point a profile at the base config's cluster and
+/// tables, so a profile either sets them itself or derives/discovers its own.
+const PROVISIONED_FIELDS: [&[&str]; 5] = [
+    &["dsql", "identifier"],
+    &["dsql", "rate_coordination", "table_name"],
+    &["dsql", "conn_lease", "table_name"],
+    &["dynamodb", "rate_limiter_table"],
+    &["dynamodb", "conn_lease_table"],
+];
+
+/// Remove the value at a dotted `path`, if present.
+fn remove_path(table: &mut toml::Table, path: &[&str]) {
+    match path {
+        [] => {}
+        [key] => {
+            table.remove(*key);
+        }
+        [key, rest @ ..] => {
+            if let Some(toml::Value::Table(child)) = table.get_mut(*key) {
+                remove_path(child, rest);
+            }
+        }
+    }
+}
+
+/// Recursively overlay `overlay` onto `base`. Tables merge key by key;
+/// any other value replaces the base value outright.
+fn merge_tables(base: &mut toml::Table, overlay: toml::Table) {
+    for (key, value) in overlay {
+        match (base.get_mut(&key), value) {
+            (Some(toml::Value::Table(base_table)), toml::Value::Table(overlay_table)) => {
+                merge_tables(base_table, overlay_table);
+            }
+            (_, value) => {
+                base.insert(key, value);
+            }
+        }
+    }
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -89,4 +177,108 @@ identifier = "my-cluster-id"
 
         std::fs::remove_dir_all(&dir).ok();
     }
+
+    const PROFILED: &str = r#"
+[project]
+name = "temporal-dev"
+region = "eu-west-1"
+
+[dsql]
+identifier = "dev-cluster"
+user = "admin"
+max_conns = 50
+max_idle_conns = 50
+
+[profiles.prod.project]
+name = "temporal-prod"
+
+[profiles.prod.dsql]
+identifier = "prod-cluster"
+user = "temporal"
+"#;
+
+    #[test]
+    fn parse_config_without_env_ignores_profiles() {
+        let config = parse_config(PROFILED, None).unwrap();
+        assert_eq!(config.project.name, "temporal-dev");
+        assert_eq!(config.dsql.identifier, "dev-cluster");
+        assert_eq!(config.dsql.user, "admin");
+    }
+
+    #[test]
+    fn parse_config_overlays_profile_and_inherits_the_rest() {
+        let config = parse_config(PROFILED, Some("prod")).unwrap();
+        assert_eq!(config.project.name, "temporal-prod");
+        assert_eq!(config.project.region, "eu-west-1");
+        assert_eq!(config.dsql.identifier, "prod-cluster");
+        assert_eq!(config.dsql.user, "temporal");
+        assert_eq!(config.dsql.max_conns, 50);
+    }
+
+    #[test]
+    fn parse_config_profile_does_not_inherit_provisioned_resources() {
+        let contents = r#"
+[project]
+name = "temporal-dev"
+
+[dsql]
+identifier = "dev-cluster"
+user = "temporal"
+
+[dsql.rate_coordination]
+table_name = "temporal-dev-dsql-rate-limiter"
+
+[dsql.conn_lease]
+table_name = "temporal-dev-dsql-conn-lease"
+
+[dynamodb]
+rate_limiter_table = "temporal-dev-dsql-rate-limiter"
+conn_lease_table = "temporal-dev-dsql-conn-lease"
+
+[profiles.stage.project]
+name = "temporal-stage"
+"#;
+        let config = parse_config(contents, Some("stage")).unwrap();
+        assert_eq!(config.project.name, "temporal-stage");
+        assert_eq!(config.dsql.user, "temporal");
+        assert!(config.dsql.identifier.is_empty());
+        assert!(config.dsql.rate_coordination.table_name.is_empty());
+        assert!(config.dsql.conn_lease.table_name.is_empty());
+        assert!(config.dynamodb.rate_limiter_table.is_empty());
+        assert!(config.dynamodb.conn_lease_table.is_empty());
+
+        let base = parse_config(contents, None).unwrap();
+        assert_eq!(base.dsql.identifier, "dev-cluster");
+    }
+
+    #[test]
+    fn parse_config_profile_env_tag_defaults_to_profile_name() {
+        let contents = r#"
+[project.tags]
+team = "platform"
+env = "dev"
+service = "temporal"
+
+[profiles.prod.project]
+name = "temporal-prod"
+
+[profiles.stage.project.tags]
+env = "staging"
+"#;
+        let base = parse_config(contents, None).unwrap();
+        assert_eq!(base.project.tags["env"], "dev");
+
+        let prod = parse_config(contents, Some("prod")).unwrap();
+        assert_eq!(prod.project.tags["env"], "prod");
+        assert_eq!(prod.project.tags["team"], "platform");
+
+        let stage = parse_config(contents, Some("stage")).unwrap();
+        assert_eq!(stage.project.tags["env"], "staging");
+    }
+
+    #[test]
+    fn parse_config_unknown_profile() {
+        let result = parse_config(PROFILED, Some("stage"));
+        assert!(matches!(result, Err(ConfigError::UnknownProfile(ref p)) if p == "stage"));
+    }
 }
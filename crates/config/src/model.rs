@@ -1,3 +1,5 @@
+use std::collections::BTreeMap;
+
 use serde::{Deserialize, Serialize};
 
 // ─── Default helper functions ───────────────────────────────────────────────
@@ -102,6 +104,10 @@ fn default_temporal_image() -> String {
     "temporal-dsql-server:latest".to_string()
 }
 
+fn default_ca_file() -> String {
+    "/etc/temporal/certs/amazon-root-ca-bundle.pem".to_string()
+}
+
 // ─── Config structs ─────────────────────────────────────────────────────────
 
 #[derive(Debug, Clone, Default, Serialize, Deserialize)]
@@ -124,6 +130,10 @@ pub struct ProjectSection {
     pub name: String,
     #[serde(default = "default_region")]
     pub region: String,
+    /// Cost-allocation tags applied to every provisioned resource.
+    /// `dsqld infra apply` requires `team`, `env` and `service`.
+    #[serde(default)]
+    pub tags: BTreeMap<String, String>,
 }
 
 impl Default for ProjectSection {
@@ -131,6 +141,7 @@ impl Default for ProjectSection {
         Self {
             name: default_project_name(),
             region: default_region(),
+            tags: BTreeMap::new(),
         }
     }
 }
@@ -161,6 +172,8 @@ pub struct DsqlSection {
     pub rate_coordination: RateCoordinationConfig,
     #[serde(default)]
     pub conn_lease: ConnLeaseConfig,
+    #[serde(default)]
+    pub tls: TlsConfig,
 }
 
 impl Default for DsqlSection {
@@ -177,6 +190,7 @@ impl Default for DsqlSection {
             reservoir: ReservoirConfig::default(),
             rate_coordination: RateCoordinationConfig::default(),
             conn_lease: ConnLeaseConfig::default(),
+            tls: TlsConfig::default(),
         }
     }
 }
@@ -290,6 +304,27 @@ impl Default for ConnLeaseConfig {
     }
 }
 
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct TlsConfig {
+    /// CA bundle path inside the Temporal containers. The default pins to the
+    /// Amazon Trust Services roots shipped in dev/certs/.
+    #[serde(default = "default_ca_file")]
+    pub ca_file: String,
+    /// Verify the server certificate against the cluster endpoint
+    /// (equivalent to `sslmode=verify-full`).
+    #[serde(default = "default_true")]
+    pub enable_host_verification: bool,
+}
+
+impl Default for TlsConfig {
+    fn default() -> Self {
+        Self {
+            ca_file: default_ca_file(),
+            enable_host_verification: default_true(),
+        }
+    }
+}
+
 #[derive(Debug, Clone, Serialize, Deserialize)]
 pub struct ElasticsearchSection {
     #[serde(default = "default_es_host")]
@@ -21,6 +21,9 @@ pub enum ConfigError {
 
     #[error("missing required field: {0}")]
     MissingField(String),
+
+    #[error("unknown profile '{0}' — define [profiles.{0}] in config.toml")]
+    UnknownProfile(String),
 }
 
 /// Validate config invariants. Returns Err on first violation.
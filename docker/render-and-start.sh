@@ -38,6 +38,9 @@ REQUIRED_VARS=(
     TEMPORAL_SQL_MAX_CONNS
     TEMPORAL_SQL_MAX_IDLE_CONNS
     TEMPORAL_SQL_TLS_ENABLED
+    TEMPORAL_SQL_CA_FILE
+    TEMPORAL_SQL_TLS_ENABLE_HOST_VERIFICATION
+    TEMPORAL_SQL_SERVER_NAME
     TEMPORAL_HISTORY_SHARDS
     TEMPORAL_ELASTICSEARCH_VERSION
     TEMPORAL_ELASTICSEARCH_SCHEME